@@ -0,0 +1,106 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/slaveofcode/voodio/workers"
+)
+
+// DoExtraction runs ffmpeg against sourcePath, producing an HLS playlist plus
+// its segments for the given resolution under outputDir. report is called as
+// ffmpeg parses progress, and totalDurationSec is used to turn elapsed time
+// into a percentage.
+func DoExtraction(ctx context.Context, ffmpegBin, sourcePath, outputDir, resolution string, totalDurationSec float64, report func(percent float64)) error {
+	if err := os.MkdirAll(outputDir, 0777); err != nil {
+		return fmt.Errorf("unable to create output dir %s: %w", outputDir, err)
+	}
+
+	scale, err := resolutionScale(resolution)
+	if err != nil {
+		return err
+	}
+
+	playlistPath := filepath.Join(outputDir, resolution+".m3u8")
+	segmentPath := filepath.Join(outputDir, resolution+"_%03d.ts")
+
+	cmd := exec.CommandContext(ctx, ffmpegBin,
+		"-i", sourcePath,
+		"-vf", "scale="+scale,
+		"-start_number", "0",
+		"-hls_time", "10",
+		"-hls_list_size", "0",
+		"-f", "hls",
+		"-hls_segment_filename", segmentPath,
+		"-progress", "pipe:1",
+		"-nostats",
+		playlistPath,
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if err := workers.ParseFFmpegProgress(stdout, totalDurationSec, report); err != nil {
+		cmd.Wait()
+		return err
+	}
+
+	return cmd.Wait()
+}
+
+// ProbeDuration runs ffprobe against sourcePath and returns its duration in
+// seconds, so DoExtraction's caller can turn ffmpeg's -progress output into
+// an actual completion percentage instead of skipping progress reporting
+// altogether. ffprobeBin is resolved next to ffmpegBin, since the two ship
+// together in every common FFmpeg distribution.
+func ProbeDuration(ffmpegBin, sourcePath string) (float64, error) {
+	ffprobeBin := filepath.Join(filepath.Dir(ffmpegBin), "ffprobe")
+	if _, err := exec.LookPath(ffprobeBin); err != nil {
+		ffprobeBin = "ffprobe"
+	}
+
+	cmd := exec.Command(ffprobeBin,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		sourcePath,
+	)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed for %s: %w", sourcePath, err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse ffprobe duration %q: %w", strings.TrimSpace(string(out)), err)
+	}
+
+	return duration, nil
+}
+
+func resolutionScale(resolution string) (string, error) {
+	switch resolution {
+	case "360p":
+		return "-2:360", nil
+	case "480p":
+		return "-2:480", nil
+	case "720p":
+		return "-2:720", nil
+	case "1080p":
+		return "-2:1080", nil
+	default:
+		return "", fmt.Errorf("unsupported resolution: %s", resolution)
+	}
+}
@@ -0,0 +1,77 @@
+package collections
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var videoExts = map[string]bool{
+	".mp4":  true,
+	".mkv":  true,
+	".avi":  true,
+	".mov":  true,
+	".webm": true,
+}
+
+var subtitleExts = map[string]bool{
+	".srt": true,
+	".sub": true,
+	".vtt": true,
+	".ass": true,
+}
+
+// MovieDirInfo is a single video file discovered under the scanned parent
+// directory.
+type MovieDirInfo struct {
+	Dir       string
+	MovieFile string
+	MovieSize float64
+	MimeType  string
+}
+
+// SubDirInfo is a single subtitle file discovered under the scanned parent
+// directory.
+type SubDirInfo struct {
+	Dir     string
+	SubFile string
+}
+
+// ScanDir walks parentPath recursively and returns every movie and subtitle
+// file found.
+func ScanDir(parentPath string) ([]MovieDirInfo, []SubDirInfo, error) {
+	var movies []MovieDirInfo
+	var subs []SubDirInfo
+
+	err := filepath.Walk(parentPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		dir := filepath.Dir(path)
+
+		switch {
+		case videoExts[ext]:
+			movies = append(movies, MovieDirInfo{
+				Dir:       dir,
+				MovieFile: filepath.Base(path),
+				MovieSize: float64(info.Size()),
+				MimeType:  mime.TypeByExtension(ext),
+			})
+		case subtitleExts[ext]:
+			subs = append(subs, SubDirInfo{
+				Dir:     dir,
+				SubFile: filepath.Base(path),
+			})
+		}
+
+		return nil
+	})
+
+	return movies, subs, err
+}
@@ -0,0 +1,143 @@
+package collections
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatchEventType describes the kind of change a Watcher detected on a path,
+// already debounced and coalesced from the raw fsnotify events.
+type WatchEventType int
+
+const (
+	// WatchEventUpsert means a path should be (re)scanned and its
+	// corresponding row inserted or updated.
+	WatchEventUpsert WatchEventType = iota
+	// WatchEventRemove means a path is gone and its row should be marked
+	// as gone / cleaned up.
+	WatchEventRemove
+)
+
+// WatchEvent is the debounced result handed to a Watcher's consumer.
+type WatchEvent struct {
+	Type WatchEventType
+	Path string
+}
+
+// Watcher observes a parent movie directory recursively and reports file
+// changes, debounced per path so that downloaders which create a folder and
+// then move large files into it over time don't trigger a burst of
+// half-finished scans.
+type Watcher struct {
+	rootPath string
+	delay    time.Duration
+	fsw      *fsnotify.Watcher
+	events   chan WatchEvent
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// NewWatcher creates a Watcher rooted at rootPath. delay controls how long a
+// path must stay quiet before an event is emitted (e.g. 5-30s).
+func NewWatcher(rootPath string, delay time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		rootPath: rootPath,
+		delay:    delay,
+		fsw:      fsw,
+		events:   make(chan WatchEvent),
+		pending:  make(map[string]*time.Timer),
+	}
+
+	if err := w.addRecursive(rootPath); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Events returns the channel of debounced, deduplicated changes.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Start begins processing raw fsnotify events until stop is closed.
+func (w *Watcher) Start(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			w.fsw.Close()
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleRawEvent(ev)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Errorln("watcher error:", err)
+		}
+	}
+}
+
+func (w *Watcher) handleRawEvent(ev fsnotify.Event) {
+	switch {
+	case ev.Op&fsnotify.Create == fsnotify.Create:
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			w.addRecursive(ev.Name)
+		}
+		w.debounce(ev.Name, WatchEventUpsert)
+	case ev.Op&fsnotify.Chmod == fsnotify.Chmod:
+		w.debounce(ev.Name, WatchEventUpsert)
+	case ev.Op&fsnotify.Rename == fsnotify.Rename:
+		w.debounce(ev.Name, WatchEventRemove)
+	case ev.Op&fsnotify.Remove == fsnotify.Remove:
+		w.debounce(ev.Name, WatchEventRemove)
+	case ev.Op&fsnotify.Write == fsnotify.Write:
+		w.debounce(ev.Name, WatchEventUpsert)
+	}
+}
+
+// debounce resets the per-path timer every time a new raw event arrives for
+// that path, only emitting once the path has been quiet for w.delay.
+func (w *Watcher) debounce(path string, evType WatchEventType) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t, ok := w.pending[path]; ok {
+		t.Stop()
+	}
+
+	w.pending[path] = time.AfterFunc(w.delay, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+
+		w.events <- WatchEvent{Type: evType, Path: path}
+	})
+}
+
+func (w *Watcher) addRecursive(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return w.fsw.Add(path)
+		}
+		return nil
+	})
+}
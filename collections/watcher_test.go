@@ -0,0 +1,59 @@
+package collections
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestWatcher(delay time.Duration) *Watcher {
+	return &Watcher{
+		delay:   delay,
+		events:  make(chan WatchEvent, 1),
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+func TestDebounceCoalescesRapidEvents(t *testing.T) {
+	w := newTestWatcher(20 * time.Millisecond)
+
+	w.debounce("/movies/foo", WatchEventUpsert)
+	w.debounce("/movies/foo", WatchEventUpsert)
+	w.debounce("/movies/foo", WatchEventUpsert)
+
+	select {
+	case <-w.events:
+		t.Fatal("expected no event before the debounce delay has elapsed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case ev := <-w.events:
+		if ev.Path != "/movies/foo" || ev.Type != WatchEventUpsert {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected exactly one coalesced event after the delay")
+	}
+
+	select {
+	case ev := <-w.events:
+		t.Fatalf("expected only one event, got a second: %+v", ev)
+	default:
+	}
+}
+
+func TestDebounceLastEventTypeWins(t *testing.T) {
+	w := newTestWatcher(20 * time.Millisecond)
+
+	w.debounce("/movies/bar", WatchEventUpsert)
+	w.debounce("/movies/bar", WatchEventRemove)
+
+	select {
+	case ev := <-w.events:
+		if ev.Type != WatchEventRemove {
+			t.Fatalf("got type %v, want %v", ev.Type, WatchEventRemove)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected a debounced event")
+	}
+}
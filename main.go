@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
@@ -19,10 +20,13 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/slaveofcode/voodio/collections"
 	"github.com/slaveofcode/voodio/logger"
+	"github.com/slaveofcode/voodio/metadata"
+	"github.com/slaveofcode/voodio/pubsub"
 	"github.com/slaveofcode/voodio/repository"
 	"github.com/slaveofcode/voodio/repository/models"
 	"github.com/slaveofcode/voodio/web"
 	"github.com/slaveofcode/voodio/web/config"
+	"github.com/slaveofcode/voodio/workers"
 )
 
 const (
@@ -54,28 +58,23 @@ func init() {
 		log.Infoln("Created App dir at", appDirPath)
 	}
 
-	// remove old database if exist
+	// create the database file only if it doesn't exist yet, so watched
+	// state, scores and prepared-HLS pointers survive a restart
 	dbPath := getDBPath()
-	_, err := os.Stat(dbPath)
-	if !os.IsNotExist(err) {
-		log.Infoln("Obsolete DB detected, removing...")
-		if err = os.Remove(dbPath); err != nil {
-			panic("Unable removing obsolete DB")
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		if _, err := os.Create(dbPath); err != nil {
+			log.Errorln("Unable to init db file", err)
+			os.Exit(1)
 		}
+		log.Infoln("DB initialized at", dbPath)
 	}
-
-	_, err = os.Create(dbPath)
-	if err != nil {
-		log.Errorln("Unable to init db file", err)
-		os.Exit(1)
-	}
-
-	log.Infoln("DB initialized at", dbPath)
 }
 
 func cleanup() {
 	log.Infoln("Cleaning up artifacts")
-	os.RemoveAll(getAppDir())
+	// Only purge transient HLS output, never the DB itself - that's what
+	// makes watched state, scores and playback position persist.
+	os.RemoveAll(filepath.Join(getAppDir(), "hls"))
 }
 
 type resolutionParam []string
@@ -93,6 +92,10 @@ func main() {
 	parentMoviePath := flag.String("path", "", "Path string of parent movie directory")
 	serverPort := flag.Int("port", 1818, "Server port number")
 	tmdbAPIKey := flag.String("tmdb-key", "", "Your TMDB Api Key, get here if you don't have one https://www.themoviedb.org/documentation/api")
+	omdbAPIKey := flag.String("omdb-key", "", "Your OMDB Api Key, get here if you don't have one http://www.omdbapi.com/apikey.aspx")
+	metadataProviders := flag.String("metadata-provider", "tmdb", "Ordered, comma separated fallback chain of metadata providers: tmdb, omdb, nfo")
+	watchDebounceSec := flag.Int("watch-debounce", 10, "Seconds to wait for a path to stay quiet before it's (re)scanned")
+	numWorkers := flag.Int("workers", 0, "Number of concurrent FFmpeg transcode workers, defaults to NumCPU")
 
 	screenRes := resolutionParam{}
 	flag.Var(&screenRes, "resolution", "Specific resolution to be processed: 360p, 480p, 720p and 1080p, this could be multiple")
@@ -118,9 +121,16 @@ func main() {
 		panic("sorry, you haven't install ffmpeg, INSTALL FFMPEG first!")
 	}
 
-	if len(strings.TrimSpace(*tmdbAPIKey)) == 0 {
+	ffmpegBin, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		cleanup()
+		panic("Unable to resolve ffmpeg binary path: " + err.Error())
+	}
+
+	providerChain, err := buildMetadataChain(*metadataProviders, *parentMoviePath, *tmdbAPIKey, *omdbAPIKey)
+	if err != nil {
 		cleanup()
-		panic("No TMDB Api Key provided, exited")
+		panic("Unable to set up metadata providers: " + err.Error())
 	}
 
 	dbConn, err := repository.OpenDB(getDBPath())
@@ -131,12 +141,25 @@ func main() {
 
 	defer dbConn.Close()
 
+	// eventBus fans import/transcode/library progress out to any client
+	// connected to /ws/events, replacing plain log lines as the primary way
+	// of observing long-running work.
+	eventBus := pubsub.NewBus()
+
 	log.Infoln("Preparing database...")
-	repository.Migrate(dbConn)
+	if err := repository.Migrate(dbConn); err != nil {
+		cleanup()
+		panic("Unable to run database migrations: " + err.Error())
+	}
 	log.Infoln("Database prepared")
 
-	// Scan movies inside given path
-	log.Infoln("Scanning movies...")
+	// Reconcile the DB against what's on disk. The DB now survives
+	// restarts, so this must not blindly re-insert the whole library -
+	// saveMovies/saveSubs below update existing rows by dir+file instead
+	// of creating duplicates, and only scrape metadata for rows that are
+	// actually new.
+	log.Infoln("Reconciling library with existing database...")
+	eventBus.Publish(pubsub.Message{Type: pubsub.EventImportBegin})
 	movies, subs, err := collections.ScanDir(*parentMoviePath)
 	if err != nil {
 		cleanup()
@@ -144,39 +167,40 @@ func main() {
 	}
 	log.Infoln("Scanning movies finished")
 
-	saveMovies(dbConn, movies)
-	saveSubs(dbConn, subs)
-
-	// Find duplicate directory names, kind of serial movie
-	var movieGroups []models.Movie
-	dbConn.Table("movies").
-		Select("dir_name, dir_path, COUNT(*) count").
-		Group("dir_name, dir_path").
-		Having("count > ?", 1).
-		Find(&movieGroups)
+	saveMovies(dbConn, eventBus, providerChain, getAppDir(), movies, subs)
+	saveSubs(dbConn, movies, subs)
+	markGroupDirs(dbConn)
+	eventBus.Publish(pubsub.Message{Type: pubsub.EventImportEnd})
 
-	for _, mg := range movieGroups {
-		// find related movie with same dir_name & dir_path
-		var movieList []models.Movie
-		dbConn.Where(&models.Movie{
-			DirName: mg.DirName,
-			DirPath: mg.DirPath,
-		}).Find(&movieList)
-
-		for _, m := range movieList {
-			dbConn.Model(&m).Update(&models.Movie{
-				IsGroupDir: true,
-			})
-		}
+	// Keep watching the path for incremental changes so new movies can be
+	// dropped in without restarting voodio
+	watchDelay := time.Duration(*watchDebounceSec) * time.Second
+	watcher, err := collections.NewWatcher(*parentMoviePath, watchDelay)
+	if err != nil {
+		cleanup()
+		panic("Unable to start directory watcher: " + err.Error())
 	}
 
+	watcherStop := make(chan struct{})
+	go watcher.Start(watcherStop)
+	go handleWatchEvents(dbConn, eventBus, providerChain, watcher)
+
+	// Worker pool owns the FFmpeg transcode jobs triggered by the "prepare
+	// movie" flow, supervised for the lifetime of the process
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	workerPool := workers.NewPool(*numWorkers, eventBus)
+	workerPool.Start(workerCtx)
+
 	// create simple webserver
 	webServer := web.NewServer(&config.ServerConfig{
 		DB:                dbConn,
 		Port:              *serverPort,
 		AppDir:            getAppDir(),
-		TMDBApiKey:        *tmdbAPIKey,
+		FFmpegBin:         ffmpegBin,
+		MetadataProviders: providerChain,
 		ScreenResolutions: screenRes,
+		WorkerPool:        workerPool,
+		EventBus:          eventBus,
 	})
 
 	closeSignal := make(chan os.Signal, 1)
@@ -188,6 +212,9 @@ func main() {
 		<-closeSignal
 		log.Infoln("Shutting down...")
 
+		close(watcherStop)
+		stopWorkers()
+
 		// Waiting for current process server to finish with 30 secs timeout
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 		defer cancel()
@@ -226,8 +253,9 @@ func showIPServer(port int) {
 	}
 }
 
-func saveMovies(dbConn *gorm.DB, movies []collections.MovieDirInfo) {
-	for _, movie := range movies {
+func saveMovies(dbConn *gorm.DB, bus *pubsub.Bus, chain metadata.Chain, appDir string, movies []collections.MovieDirInfo, subs []collections.SubDirInfo) {
+	total := len(movies)
+	for i, movie := range movies {
 		dirName := filepath.Base(movie.Dir)
 		dirNameParsedInfo, err := parsetorrentname.Parse(filepath.Base(movie.Dir))
 		cleanDirName := ""
@@ -241,22 +269,126 @@ func saveMovies(dbConn *gorm.DB, movies []collections.MovieDirInfo) {
 			cleanBaseName = baseNameParsedInfo.Title
 		}
 
-		dbConn.Create(&models.Movie{
-			DirPath:       movie.Dir,
-			DirName:       dirName,
-			CleanDirName:  cleanDirName,
-			FileSize:      movie.MovieSize,
-			BaseName:      movie.MovieFile,
-			CleanBaseName: cleanBaseName,
-			MimeType:      movie.MimeType,
-			IsGroupDir:    false,
-			IsPrepared:    false,
+		var row models.Movie
+		isNew := dbConn.Where(&models.Movie{
+			DirPath:  movie.Dir,
+			BaseName: movie.MovieFile,
+		}).First(&row).RecordNotFound()
+
+		row.DirPath = movie.Dir
+		row.DirName = dirName
+		row.CleanDirName = cleanDirName
+		row.FileSize = movie.MovieSize
+		row.BaseName = movie.MovieFile
+		row.CleanBaseName = cleanBaseName
+		row.MimeType = movie.MimeType
+		row.IsGone = false
+		row.MoreSources = buildAlternateSources(movie, movies, subs)
+
+		if isNew {
+			dbConn.Create(&row)
+		} else {
+			dbConn.Save(&row)
+		}
+
+		if isNew {
+			go scrapeMovie(dbConn, chain, appDir, &row)
+		}
+
+		if bus != nil {
+			bus.Publish(pubsub.Message{
+				Type: pubsub.EventImportProgress,
+				Payload: map[string]interface{}{
+					"movie_id": row.ID,
+					"current":  i + 1,
+					"total":    total,
+				},
+			})
+		}
+	}
+}
+
+// buildAlternateSources collects sibling files next to a movie's primary
+// BaseName - extra video/audio tracks and subtitles already captured in
+// subs - so they can be attached to the same row instead of creating
+// disconnected ones. movies is the full scan result, used to exclude every
+// other real movie sharing the directory, not just movie's own file.
+func buildAlternateSources(movie collections.MovieDirInfo, movies []collections.MovieDirInfo, subs []collections.SubDirInfo) models.AlternateSources {
+	var sources models.AlternateSources
+
+	siblings, err := ioutil.ReadDir(movie.Dir)
+	if err == nil {
+		for _, f := range siblings {
+			if f.IsDir() || isKnownMovieFile(movies, movie.Dir, f.Name()) {
+				continue
+			}
+
+			switch strings.ToLower(filepath.Ext(f.Name())) {
+			case ".mp4", ".mkv", ".avi", ".mov", ".webm":
+				sources = append(sources, models.AlternateSource{
+					Name: f.Name(),
+					Type: models.SourceTypeVideo,
+					URL:  filepath.Join(movie.Dir, f.Name()),
+				})
+			case ".mp3", ".aac", ".ac3", ".flac", ".dts":
+				sources = append(sources, models.AlternateSource{
+					Name: f.Name(),
+					Type: models.SourceTypeAudio,
+					URL:  filepath.Join(movie.Dir, f.Name()),
+				})
+			}
+		}
+	}
+
+	for _, sub := range subs {
+		if sub.Dir != movie.Dir {
+			continue
+		}
+
+		sources = append(sources, models.AlternateSource{
+			Name:     sub.SubFile,
+			Type:     models.SourceTypeSubtitle,
+			URL:      filepath.Join(sub.Dir, sub.SubFile),
+			Language: subLanguage(sub.SubFile),
 		})
 	}
+
+	return sources
+}
+
+// subLanguage pulls a language code out of a subtitle filename following the
+// common "movie.en.srt" convention.
+func subLanguage(fileName string) string {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	parts := strings.Split(base, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	candidate := parts[len(parts)-1]
+	if len(candidate) == 2 || len(candidate) == 3 {
+		return strings.ToLower(candidate)
+	}
+
+	return ""
 }
 
-func saveSubs(dbConn *gorm.DB, subs []collections.SubDirInfo) {
+// saveSubs stores subtitles that don't already live next to one of movies -
+// subs matching a movie's directory are attached as MoreSources instead.
+func saveSubs(dbConn *gorm.DB, movies []collections.MovieDirInfo, subs []collections.SubDirInfo) {
 	for _, sub := range subs {
+		if hasMovieInDir(movies, sub.Dir) {
+			continue
+		}
+
+		var existing models.Subtitle
+		if !dbConn.Where(&models.Subtitle{
+			DirPath:  sub.Dir,
+			BaseName: sub.SubFile,
+		}).First(&existing).RecordNotFound() {
+			continue
+		}
+
 		dirName := filepath.Base(sub.Dir)
 		dirNameParsedInfo, err := parsetorrentname.Parse(filepath.Base(sub.Dir))
 		cleanDirName := ""
@@ -280,6 +412,225 @@ func saveSubs(dbConn *gorm.DB, subs []collections.SubDirInfo) {
 	}
 }
 
+func hasMovieInDir(movies []collections.MovieDirInfo, dir string) bool {
+	for _, m := range movies {
+		if m.Dir == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownMovieFile reports whether fileName in dir belongs to one of the
+// movies discovered by this scan, so it isn't misattributed as an alternate
+// source of a sibling movie sharing the same directory.
+func isKnownMovieFile(movies []collections.MovieDirInfo, dir, fileName string) bool {
+	for _, m := range movies {
+		if m.Dir == dir && m.MovieFile == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// markGroupDirs finds duplicate directory names, kind of serial movie, and
+// flags the related rows as part of a group. This is a full-table scan,
+// meant to run once at startup against the freshly imported library.
+func markGroupDirs(dbConn *gorm.DB) {
+	var movieGroups []models.Movie
+	dbConn.Table("movies").
+		Select("dir_name, dir_path, COUNT(*) count").
+		Group("dir_name, dir_path").
+		Having("count > ?", 1).
+		Find(&movieGroups)
+
+	for _, mg := range movieGroups {
+		markGroupDirForPath(dbConn, mg.DirName, mg.DirPath)
+	}
+}
+
+// markGroupDirForPath re-evaluates the group-dir flag for a single
+// directory, without rescanning the rest of the table. handleWatchEvents
+// uses this after every upsert event so incremental imports stay cheap.
+func markGroupDirForPath(dbConn *gorm.DB, dirName, dirPath string) {
+	var movieList []models.Movie
+	dbConn.Where(&models.Movie{
+		DirName: dirName,
+		DirPath: dirPath,
+	}).Find(&movieList)
+
+	isGroup := len(movieList) > 1
+	for _, m := range movieList {
+		if m.IsGroupDir == isGroup {
+			continue
+		}
+
+		dbConn.Model(&m).Update("is_group_dir", isGroup)
+	}
+}
+
+// handleWatchEvents consumes debounced filesystem events off watcher and
+// keeps the movies/subtitles tables in sync with what's on disk, without
+// needing a full restart of voodio.
+func handleWatchEvents(dbConn *gorm.DB, bus *pubsub.Bus, chain metadata.Chain, watcher *collections.Watcher) {
+	for ev := range watcher.Events() {
+		switch ev.Type {
+		case collections.WatchEventRemove:
+			markPathGone(dbConn, resolveGoneDir(dbConn, ev.Path))
+		case collections.WatchEventUpsert:
+			// Rescan the whole containing directory rather than ev.Path
+			// alone, so saveMovies/saveSubs see every movie and subtitle
+			// sharing it.
+			dir := resolveExistingDir(ev.Path)
+			movies, subs, err := collections.ScanDir(dir)
+			if err != nil {
+				log.Errorln("watcher: unable to rescan", dir, err)
+				continue
+			}
+
+			saveMovies(dbConn, bus, chain, getAppDir(), movies, subs)
+			saveSubs(dbConn, movies, subs)
+
+			markGroupDirForPath(dbConn, filepath.Base(dir), dir)
+		}
+
+		bus.Publish(pubsub.Message{
+			Type:    pubsub.EventLibraryChanged,
+			Payload: map[string]interface{}{"path": ev.Path},
+		})
+	}
+}
+
+// resolveExistingDir returns ev.Path's containing directory when ev.Path is
+// a file still on disk - the common case, a single movie file landing
+// inside an already-watched directory - or ev.Path itself when it's a
+// directory, matching how ScanDir/saveMovies derive DirPath.
+func resolveExistingDir(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path
+	}
+	return filepath.Dir(path)
+}
+
+// resolveGoneDir mirrors resolveExistingDir for a path that's already gone
+// from disk (a Remove event), where os.Stat can no longer tell a removed
+// file from a removed directory. It checks whether path itself was a
+// tracked directory before assuming it was a file inside one.
+func resolveGoneDir(dbConn *gorm.DB, path string) string {
+	var count int
+	dbConn.Model(&models.Movie{}).Where("dir_path = ?", path).Count(&count)
+	if count > 0 {
+		return path
+	}
+	return filepath.Dir(path)
+}
+
+// markPathGone flags rows under a removed path as gone and purges any
+// prepared HLS artifacts that pointed at them.
+func markPathGone(dbConn *gorm.DB, path string) {
+	var movies []models.Movie
+	dbConn.Where("dir_path = ?", path).Find(&movies)
+
+	for _, m := range movies {
+		if m.IsPrepared {
+			os.RemoveAll(filepath.Join(getAppDir(), "hls", strconv.Itoa(int(m.ID))))
+		}
+
+		dbConn.Model(&m).Update(&models.Movie{
+			IsGone: true,
+		})
+	}
+
+	dbConn.Where("dir_path = ?", path).Delete(&models.Subtitle{})
+}
+
+// buildMetadataChain turns the -metadata-provider flag (e.g. "tmdb,omdb,nfo")
+// into an ordered metadata.Chain, so a library without a TMDB key can still
+// fall back to OMDB or local .nfo/movie.json sidecar files.
+func buildMetadataChain(providers, moviePath, tmdbKey, omdbKey string) (metadata.Chain, error) {
+	chain := metadata.Chain{}
+
+	for _, name := range strings.Split(providers, ",") {
+		switch strings.TrimSpace(name) {
+		case "tmdb":
+			if len(strings.TrimSpace(tmdbKey)) == 0 {
+				continue
+			}
+			chain = append(chain, metadata.NewTMDBProvider(tmdbKey))
+		case "omdb":
+			if len(strings.TrimSpace(omdbKey)) == 0 {
+				continue
+			}
+			chain = append(chain, metadata.NewOMDBProvider(omdbKey))
+		case "nfo":
+			chain = append(chain, metadata.NewNFOProvider(moviePath))
+		}
+	}
+
+	if len(chain) == 0 {
+		// Every requested provider was skipped for lack of a key (e.g. the
+		// default "-metadata-provider tmdb" with no "-tmdb-key"). Fall back
+		// to the local .nfo/movie.json sidecar provider, which needs no key,
+		// instead of panicking on startup for users without a TMDB key.
+		chain = append(chain, metadata.NewNFOProvider(moviePath))
+	}
+
+	return chain, nil
+}
+
+// scrapeMovie enqueues a lookup against the provider chain for a freshly
+// inserted row, instead of relying on an on-demand lookup at render time. A
+// successful lookup's poster is fetched and cached on disk under appDir, so
+// the web layer can serve it without round-tripping to the provider.
+func scrapeMovie(dbConn *gorm.DB, chain metadata.Chain, appDir string, movie *models.Movie) {
+	meta, provider, err := chain.Lookup(movie.CleanBaseName, 0)
+	if err != nil {
+		log.Errorln("scrape failed for", movie.CleanBaseName, err)
+		return
+	}
+
+	update := map[string]interface{}{
+		"clean_base_name": meta.Title,
+		"overview":        meta.Overview,
+		"year":            meta.Year,
+	}
+
+	if posterPath, err := cachePoster(provider, appDir, movie.ID, meta.PosterPath); err != nil {
+		log.Errorln("poster fetch failed for", movie.CleanBaseName, err)
+	} else if posterPath != "" {
+		update["poster_path"] = posterPath
+	}
+
+	dbConn.Model(movie).Updates(update)
+}
+
+// cachePoster fetches the poster for the provider that produced
+// remotePosterPath and writes it under appDir/posters, returning the local
+// path to store on the row. It returns an empty path without error when the
+// provider has no poster.
+func cachePoster(provider metadata.Provider, appDir string, movieID uint, remotePosterPath string) (string, error) {
+	if remotePosterPath == "" {
+		return "", nil
+	}
+
+	data, err := provider.FetchPoster(remotePosterPath)
+	if err != nil {
+		return "", err
+	}
+
+	postersDir := filepath.Join(appDir, "posters")
+	if err := os.MkdirAll(postersDir, 0777); err != nil {
+		return "", err
+	}
+
+	posterPath := filepath.Join(postersDir, strconv.Itoa(int(movieID))+filepath.Ext(remotePosterPath))
+	if err := ioutil.WriteFile(posterPath, data, 0644); err != nil {
+		return "", err
+	}
+
+	return posterPath, nil
+}
+
 func checkFfmpegInstalled() bool {
 	_, err := exec.LookPath("ffmpeg")
 	if err != nil {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slaveofcode/voodio/collections"
+	"github.com/slaveofcode/voodio/repository"
+	"github.com/slaveofcode/voodio/repository/models"
+)
+
+func TestSubLanguage(t *testing.T) {
+	cases := map[string]string{
+		"movie.en.srt":    "en",
+		"movie.fra.srt":   "fra",
+		"movie.srt":       "",
+		"movie.subs.srt":  "",
+		"show.s01e01.srt": "",
+	}
+
+	for in, want := range cases {
+		if got := subLanguage(in); got != want {
+			t.Errorf("subLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsKnownMovieFile(t *testing.T) {
+	movies := []collections.MovieDirInfo{
+		{Dir: "/movies/show", MovieFile: "ep1.mkv"},
+		{Dir: "/movies/show", MovieFile: "ep2.mkv"},
+	}
+
+	if !isKnownMovieFile(movies, "/movies/show", "ep2.mkv") {
+		t.Fatal("expected ep2.mkv to be recognized as a known movie file")
+	}
+	if isKnownMovieFile(movies, "/movies/show", "ep2.srt") {
+		t.Fatal("did not expect a subtitle file to be recognized as a movie file")
+	}
+	if isKnownMovieFile(movies, "/movies/other", "ep1.mkv") {
+		t.Fatal("did not expect a match across different directories")
+	}
+}
+
+func TestBuildAlternateSourcesExcludesAllKnownMovies(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"ep1.mkv", "ep2.mkv", "ep1.en.srt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	movies := []collections.MovieDirInfo{
+		{Dir: dir, MovieFile: "ep1.mkv"},
+		{Dir: dir, MovieFile: "ep2.mkv"},
+	}
+	subs := []collections.SubDirInfo{
+		{Dir: dir, SubFile: "ep1.en.srt"},
+	}
+
+	sources := buildAlternateSources(movies[0], movies, subs)
+
+	for _, src := range sources {
+		if src.Type == models.SourceTypeVideo && src.Name == "ep2.mkv" {
+			t.Fatal("ep2.mkv is a real movie of its own and must not be attached as an alternate source")
+		}
+	}
+
+	var foundSub bool
+	for _, src := range sources {
+		if src.Type == models.SourceTypeSubtitle && src.Name == "ep1.en.srt" {
+			foundSub = true
+		}
+	}
+	if !foundSub {
+		t.Fatal("expected the subtitle to be attached as an alternate source")
+	}
+}
+
+func TestResolveExistingDir(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(filePath, []byte("x"), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	if got := resolveExistingDir(filePath); got != dir {
+		t.Fatalf("resolveExistingDir(file) = %q, want %q", got, dir)
+	}
+	if got := resolveExistingDir(dir); got != dir {
+		t.Fatalf("resolveExistingDir(dir) = %q, want %q", got, dir)
+	}
+}
+
+func TestResolveGoneDir(t *testing.T) {
+	db, err := repository.OpenDB(filepath.Join(t.TempDir(), "voodio.db"))
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer db.Close()
+
+	if err := repository.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	db.Create(&models.Movie{DirPath: "/movies/show", BaseName: "ep1.mkv"})
+
+	// A removed file inside a still-tracked directory: no row has
+	// dir_path == the removed file's own path, so it should fall back to
+	// the parent directory.
+	if got := resolveGoneDir(db, "/movies/show/ep1.mkv"); got != "/movies/show" {
+		t.Fatalf("resolveGoneDir(file) = %q, want /movies/show", got)
+	}
+
+	// A removed directory that was itself tracked as dir_path.
+	if got := resolveGoneDir(db, "/movies/show"); got != "/movies/show" {
+		t.Fatalf("resolveGoneDir(dir) = %q, want /movies/show", got)
+	}
+}
+
+func TestBuildMetadataChainFallsBackToNFOWithoutAKey(t *testing.T) {
+	chain, err := buildMetadataChain("tmdb", "/movies", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) != 1 {
+		t.Fatalf("got %d providers, want 1", len(chain))
+	}
+	if chain[0].Name() != "nfo" {
+		t.Fatalf("got provider %q, want nfo", chain[0].Name())
+	}
+}
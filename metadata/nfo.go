@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NFOProvider reads metadata from sidecar files sitting next to the video,
+// à la Kodi: either a `movie.json` or a Kodi-style `.nfo` XML file. It's the
+// only provider that works fully offline.
+type NFOProvider struct {
+	RootPath string
+}
+
+// NewNFOProvider creates an NFOProvider that looks for sidecar files under
+// rootPath (voodio's configured movie path).
+func NewNFOProvider(rootPath string) *NFOProvider {
+	return &NFOProvider{RootPath: rootPath}
+}
+
+func (p *NFOProvider) Name() string {
+	return "nfo"
+}
+
+type nfoSidecarJSON struct {
+	Title    string `json:"title"`
+	Year     int    `json:"year"`
+	Overview string `json:"overview"`
+	Poster   string `json:"poster"`
+}
+
+type nfoSidecarXML struct {
+	XMLName xml.Name `xml:"movie"`
+	Title   string   `xml:"title"`
+	Year    int      `xml:"year"`
+	Plot    string   `xml:"plot"`
+	Thumb   string   `xml:"thumb"`
+}
+
+func (p *NFOProvider) Lookup(title string, year int) (*MovieMetadata, error) {
+	dir, err := p.findMovieDir(title)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "movie.json")); err == nil {
+		var sidecar nfoSidecarJSON
+		if err := json.Unmarshal(data, &sidecar); err != nil {
+			return nil, err
+		}
+		return &MovieMetadata{
+			ID:         filepath.Join(dir, "movie.json"),
+			Title:      sidecar.Title,
+			Year:       sidecar.Year,
+			Overview:   sidecar.Overview,
+			PosterPath: sidecar.Poster,
+		}, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.nfo"))
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+
+	data, err := ioutil.ReadFile(matches[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var sidecar nfoSidecarXML
+	if err := xml.Unmarshal(data, &sidecar); err != nil {
+		return nil, err
+	}
+
+	return &MovieMetadata{
+		ID:         matches[0],
+		Title:      sidecar.Title,
+		Year:       sidecar.Year,
+		Overview:   sidecar.Plot,
+		PosterPath: sidecar.Thumb,
+	}, nil
+}
+
+// FetchPoster reads the poster straight off disk when PosterPath is a local
+// file, or returns ErrNotFound when no file exists at that path.
+func (p *NFOProvider) FetchPoster(id string) ([]byte, error) {
+	data, err := ioutil.ReadFile(id)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (p *NFOProvider) findMovieDir(title string) (string, error) {
+	var found string
+	needle := strings.ToLower(title)
+
+	err := filepath.Walk(p.RootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found != "" {
+			return err
+		}
+		if info.IsDir() && strings.Contains(strings.ToLower(info.Name()), needle) {
+			found = path
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", ErrNotFound
+	}
+	return found, nil
+}
@@ -0,0 +1,99 @@
+package metadata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNFOProviderLookupJSON(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfo-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	movieDir := filepath.Join(root, "Arrival.2016")
+	if err := os.Mkdir(movieDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := `{"title":"Arrival","year":2016,"overview":"An alien film","poster":"poster.jpg"}`
+	if err := ioutil.WriteFile(filepath.Join(movieDir, "movie.json"), []byte(sidecar), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewNFOProvider(root)
+
+	meta, err := provider.Lookup("Arrival", 0)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if meta.Title != "Arrival" || meta.Year != 2016 || meta.Overview != "An alien film" {
+		t.Errorf("Lookup() = %+v, want Title=Arrival Year=2016 Overview='An alien film'", meta)
+	}
+}
+
+func TestNFOProviderLookupXML(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfo-xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	movieDir := filepath.Join(root, "Arrival.2016")
+	if err := os.Mkdir(movieDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := `<movie><title>Arrival</title><year>2016</year><plot>An alien film</plot><thumb>poster.jpg</thumb></movie>`
+	if err := ioutil.WriteFile(filepath.Join(movieDir, "arrival.nfo"), []byte(sidecar), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewNFOProvider(root)
+
+	meta, err := provider.Lookup("Arrival", 0)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if meta.Title != "Arrival" || meta.Year != 2016 || meta.Overview != "An alien film" {
+		t.Errorf("Lookup() = %+v, want Title=Arrival Year=2016 Overview='An alien film'", meta)
+	}
+}
+
+func TestNFOProviderLookupNotFound(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfo-empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	provider := NewNFOProvider(root)
+
+	if _, err := provider.Lookup("Nothing", 0); err != ErrNotFound {
+		t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNFOProviderFetchPoster(t *testing.T) {
+	root, err := ioutil.TempDir("", "nfo-poster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	posterPath := filepath.Join(root, "poster.jpg")
+	if err := ioutil.WriteFile(posterPath, []byte("poster-bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewNFOProvider(root)
+
+	data, err := provider.FetchPoster(posterPath)
+	if err != nil {
+		t.Fatalf("FetchPoster() error = %v", err)
+	}
+	if string(data) != "poster-bytes" {
+		t.Errorf("FetchPoster() = %q, want %q", data, "poster-bytes")
+	}
+}
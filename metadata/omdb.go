@@ -0,0 +1,96 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const omdbBaseURL = "https://www.omdbapi.com"
+
+// OMDBProvider looks up movie metadata from the Open Movie Database, useful
+// as a fallback when a title can't be found on TMDB.
+type OMDBProvider struct {
+	APIKey string
+
+	// baseURL defaults to the real OMDB endpoint; tests override it to
+	// point at a fake server.
+	baseURL string
+}
+
+// NewOMDBProvider creates an OMDBProvider for the given API key.
+func NewOMDBProvider(apiKey string) *OMDBProvider {
+	return &OMDBProvider{APIKey: apiKey, baseURL: omdbBaseURL}
+}
+
+func (p *OMDBProvider) Name() string {
+	return "omdb"
+}
+
+type omdbSearchResponse struct {
+	ImdbID   string `json:"imdbID"`
+	Title    string `json:"Title"`
+	Year     string `json:"Year"`
+	Plot     string `json:"Plot"`
+	Poster   string `json:"Poster"`
+	Response string `json:"Response"`
+}
+
+func (p *OMDBProvider) Lookup(title string, year int) (*MovieMetadata, error) {
+	query := url.Values{}
+	query.Set("apikey", p.APIKey)
+	query.Set("t", title)
+	if year > 0 {
+		query.Set("y", fmt.Sprintf("%d", year))
+	}
+
+	resp, err := http.Get(p.baseURL + "/?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result omdbSearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Response == "False" {
+		return nil, ErrNotFound
+	}
+
+	releaseYear := 0
+	if len(result.Year) >= 4 {
+		releaseYear, _ = strconv.Atoi(result.Year[:4])
+	}
+
+	return &MovieMetadata{
+		ID:         result.ImdbID,
+		Title:      result.Title,
+		Year:       releaseYear,
+		Overview:   result.Plot,
+		PosterPath: result.Poster,
+	}, nil
+}
+
+func (p *OMDBProvider) FetchPoster(id string) ([]byte, error) {
+	resp, err := http.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrNotFound
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
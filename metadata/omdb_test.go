@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOMDBProviderLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"imdbID":"tt2543164","Title":"Arrival","Plot":"An alien film","Poster":"https://example.com/arrival.jpg","Response":"True"}`))
+	}))
+	defer server.Close()
+
+	provider := &OMDBProvider{APIKey: "key", baseURL: server.URL}
+
+	meta, err := provider.Lookup("Arrival", 2016)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	if meta.ID != "tt2543164" || meta.Title != "Arrival" || meta.PosterPath != "https://example.com/arrival.jpg" {
+		t.Errorf("Lookup() = %+v, want ID=tt2543164 Title=Arrival PosterPath=https://example.com/arrival.jpg", meta)
+	}
+}
+
+func TestOMDBProviderLookupNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Response":"False"}`))
+	}))
+	defer server.Close()
+
+	provider := &OMDBProvider{APIKey: "key", baseURL: server.URL}
+
+	if _, err := provider.Lookup("Nothing", 0); err != ErrNotFound {
+		t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOMDBProviderFetchPoster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("poster-bytes"))
+	}))
+	defer server.Close()
+
+	provider := &OMDBProvider{}
+
+	data, err := provider.FetchPoster(server.URL)
+	if err != nil {
+		t.Fatalf("FetchPoster() error = %v", err)
+	}
+	if string(data) != "poster-bytes" {
+		t.Errorf("FetchPoster() = %q, want %q", data, "poster-bytes")
+	}
+}
+
+func TestOMDBProviderFetchPosterNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	provider := &OMDBProvider{}
+
+	if _, err := provider.FetchPoster(server.URL); err != ErrNotFound {
+		t.Errorf("FetchPoster() error = %v, want ErrNotFound", err)
+	}
+}
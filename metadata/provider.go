@@ -0,0 +1,45 @@
+package metadata
+
+import "errors"
+
+// ErrNotFound is returned by a Provider when it has no match for a lookup,
+// so a Chain can fall through to the next provider.
+var ErrNotFound = errors.New("metadata: not found")
+
+// MovieMetadata is the normalized result of a successful provider lookup.
+type MovieMetadata struct {
+	ID         string
+	Title      string
+	Year       int
+	Overview   string
+	PosterPath string
+}
+
+// Provider looks up movie metadata from a single source (TMDB, OMDB, a local
+// NFO sidecar file, ...).
+type Provider interface {
+	Name() string
+	Lookup(title string, year int) (*MovieMetadata, error)
+	FetchPoster(id string) ([]byte, error)
+}
+
+// Chain tries each Provider in order and returns the first successful
+// result, so e.g. a local .nfo file can be preferred over a network lookup,
+// or OMDB can serve as a fallback when TMDB has nothing.
+type Chain []Provider
+
+// Lookup tries every provider in order, returning the first match along with
+// the Provider that produced it. The id/PosterPath on the returned
+// MovieMetadata is only meaningful to that specific provider, so callers
+// must fetch the poster through it rather than through the chain.
+func (c Chain) Lookup(title string, year int) (*MovieMetadata, Provider, error) {
+	var lastErr error = ErrNotFound
+	for _, p := range c {
+		meta, err := p.Lookup(title, year)
+		if err == nil {
+			return meta, p, nil
+		}
+		lastErr = err
+	}
+	return nil, nil, lastErr
+}
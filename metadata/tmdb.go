@@ -0,0 +1,106 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	tmdbBaseURL       = "https://api.themoviedb.org/3"
+	tmdbPosterBaseURL = "https://image.tmdb.org/t/p/original"
+)
+
+// TMDBProvider looks up movie metadata from The Movie Database. It's the
+// default provider, matching voodio's original behavior.
+type TMDBProvider struct {
+	APIKey string
+
+	// baseURL and posterBaseURL default to the real TMDB endpoints; tests
+	// override them to point at a fake server.
+	baseURL       string
+	posterBaseURL string
+}
+
+// NewTMDBProvider creates a TMDBProvider for the given API key. Get one at
+// https://www.themoviedb.org/documentation/api
+func NewTMDBProvider(apiKey string) *TMDBProvider {
+	return &TMDBProvider{
+		APIKey:        apiKey,
+		baseURL:       tmdbBaseURL,
+		posterBaseURL: tmdbPosterBaseURL,
+	}
+}
+
+func (p *TMDBProvider) Name() string {
+	return "tmdb"
+}
+
+type tmdbSearchResponse struct {
+	Results []struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+		Overview    string `json:"overview"`
+		PosterPath  string `json:"poster_path"`
+	} `json:"results"`
+}
+
+func (p *TMDBProvider) Lookup(title string, year int) (*MovieMetadata, error) {
+	query := url.Values{}
+	query.Set("api_key", p.APIKey)
+	query.Set("query", title)
+	if year > 0 {
+		query.Set("year", fmt.Sprintf("%d", year))
+	}
+
+	resp, err := http.Get(p.baseURL + "/search/movie?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result tmdbSearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	top := result.Results[0]
+	releaseYear := 0
+	if len(top.ReleaseDate) >= 4 {
+		releaseYear, _ = strconv.Atoi(top.ReleaseDate[:4])
+	}
+	return &MovieMetadata{
+		ID:         fmt.Sprintf("%d", top.ID),
+		Title:      top.Title,
+		Year:       releaseYear,
+		Overview:   top.Overview,
+		PosterPath: top.PosterPath,
+	}, nil
+}
+
+func (p *TMDBProvider) FetchPoster(id string) ([]byte, error) {
+	resp, err := http.Get(p.posterBaseURL + id)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrNotFound
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTMDBProviderLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/movie" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"results":[{"id":42,"title":"Arrival","overview":"An alien film","poster_path":"/arrival.jpg"}]}`))
+	}))
+	defer server.Close()
+
+	provider := &TMDBProvider{APIKey: "key", baseURL: server.URL}
+
+	meta, err := provider.Lookup("Arrival", 2016)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	if meta.ID != "42" || meta.Title != "Arrival" || meta.PosterPath != "/arrival.jpg" {
+		t.Errorf("Lookup() = %+v, want ID=42 Title=Arrival PosterPath=/arrival.jpg", meta)
+	}
+}
+
+func TestTMDBProviderLookupNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	provider := &TMDBProvider{APIKey: "key", baseURL: server.URL}
+
+	if _, err := provider.Lookup("Nothing", 0); err != ErrNotFound {
+		t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTMDBProviderFetchPoster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/poster.jpg" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte("poster-bytes"))
+	}))
+	defer server.Close()
+
+	provider := &TMDBProvider{posterBaseURL: server.URL}
+
+	data, err := provider.FetchPoster("/poster.jpg")
+	if err != nil {
+		t.Fatalf("FetchPoster() error = %v", err)
+	}
+	if string(data) != "poster-bytes" {
+		t.Errorf("FetchPoster() = %q, want %q", data, "poster-bytes")
+	}
+}
+
+func TestTMDBProviderFetchPosterNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	provider := &TMDBProvider{posterBaseURL: server.URL}
+
+	if _, err := provider.FetchPoster("/missing.jpg"); err != ErrNotFound {
+		t.Errorf("FetchPoster() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,55 @@
+package pubsub
+
+import "sync"
+
+// Message is a single event published onto a Bus, shaped for direct JSON
+// serialization over the WebSocket event stream.
+type Message struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// Bus is a small fan-out pub/sub: every channel handed out by Subscribe
+// receives every Message passed to Publish.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Message]struct{}
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan Message]struct{})}
+}
+
+// Subscribe registers a new listener and returns its channel along with an
+// unsubscribe func that must be called once the listener is done.
+func (b *Bus) Subscribe() (chan Message, func()) {
+	ch := make(chan Message, 16)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish fans msg out to every current subscriber. A slow subscriber has
+// its message dropped rather than blocking the publisher.
+func (b *Bus) Publish(msg Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
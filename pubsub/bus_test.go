@@ -0,0 +1,51 @@
+package pubsub
+
+import "testing"
+
+func TestBusFanOut(t *testing.T) {
+	bus := NewBus()
+
+	ch1, unsub1 := bus.Subscribe()
+	defer unsub1()
+	ch2, unsub2 := bus.Subscribe()
+	defer unsub2()
+
+	bus.Publish(Message{Type: EventLibraryChanged})
+
+	for _, ch := range []chan Message{ch1, ch2} {
+		select {
+		case msg := <-ch:
+			if msg.Type != EventLibraryChanged {
+				t.Fatalf("got type %q, want %q", msg.Type, EventLibraryChanged)
+			}
+		default:
+			t.Fatal("expected subscriber to receive the published message")
+		}
+	}
+}
+
+func TestBusUnsubscribeStopsDelivery(t *testing.T) {
+	bus := NewBus()
+
+	ch, unsub := bus.Subscribe()
+	unsub()
+
+	bus.Publish(Message{Type: EventImportBegin})
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestBusPublishDropsWhenSubscriberIsSlow(t *testing.T) {
+	bus := NewBus()
+
+	ch, unsub := bus.Subscribe()
+	defer unsub()
+
+	// Fill the subscriber's buffer, then publish one more - Publish must not
+	// block waiting for a slow/stuck subscriber to drain.
+	for i := 0; i < cap(ch)+1; i++ {
+		bus.Publish(Message{Type: EventLibraryChanged})
+	}
+}
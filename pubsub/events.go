@@ -0,0 +1,13 @@
+package pubsub
+
+// Event type names published onto the Bus and streamed to WebSocket clients.
+const (
+	EventImportBegin       = "import:begin"
+	EventImportProgress    = "import:progress"
+	EventImportEnd         = "import:end"
+	EventTranscodeStarted  = "transcode:started"
+	EventTranscodeProgress = "transcode:progress"
+	EventTranscodeDone     = "transcode:done"
+	EventTranscodeFailed   = "transcode:failed"
+	EventLibraryChanged    = "library:changed"
+)
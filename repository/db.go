@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// OpenDB opens (creating if needed) the sqlite database at path.
+func OpenDB(path string) (*gorm.DB, error) {
+	return gorm.Open("sqlite3", path)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slaveofcode/voodio/repository/models"
+)
+
+// schemaMigration records which numbered migrations have already run
+// against a database, so Migrate only applies what's pending instead of
+// wiping and recreating the schema on every launch.
+type schemaMigration struct {
+	gorm.Model
+	Version int `gorm:"unique_index"`
+}
+
+type migration struct {
+	Version int
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// movieV1 is the frozen "movies" schema as of create_movies_and_subtitles,
+// before add_movie_playback_state introduced Watched/Score/LastPlayedAt/
+// PlaybackPositionSec. Each migration must AutoMigrate its own frozen
+// struct rather than the live models.Movie, otherwise an earlier version
+// silently picks up columns that belong to a later one and the later
+// version's Up becomes a no-op.
+type movieV1 struct {
+	gorm.Model
+
+	DirPath       string `gorm:"type:varchar(1000)"`
+	DirName       string `gorm:"type:varchar(500)"`
+	CleanDirName  string `gorm:"type:varchar(500)"`
+	FileSize      float64
+	BaseName      string `gorm:"type:varchar(500)"`
+	CleanBaseName string `gorm:"type:varchar(500)"`
+	MimeType      string `gorm:"type:varchar(100)"`
+	IsGroupDir    bool
+	IsPrepared    bool
+
+	Overview   string `gorm:"type:text"`
+	Year       int
+	PosterPath string `gorm:"type:varchar(500)"`
+
+	IsInPrepare bool
+	IsGone      bool
+
+	MoreSources models.AlternateSources `gorm:"type:text"`
+}
+
+func (movieV1) TableName() string { return "movies" }
+
+// movieV2PlaybackState carries only the columns add_movie_playback_state
+// introduces, so its Up/Down touch exactly those columns instead of
+// re-running AutoMigrate against the full, current models.Movie.
+type movieV2PlaybackState struct {
+	Watched             bool
+	Score               int
+	LastPlayedAt        time.Time
+	PlaybackPositionSec int
+}
+
+func (movieV2PlaybackState) TableName() string { return "movies" }
+
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "create_movies_and_subtitles",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&movieV1{}, &models.Subtitle{}).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.DropTable(&movieV1{}, &models.Subtitle{}).Error
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_movie_playback_state",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&movieV2PlaybackState{}).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Model(&movieV2PlaybackState{}).
+				DropColumn("watched").
+				DropColumn("score").
+				DropColumn("last_played_at").
+				DropColumn("playback_position_sec").Error
+		},
+	},
+}
+
+// Migrate brings dbConn up to the latest schema version, only applying
+// migrations that haven't been recorded in schema_migrations yet. This
+// replaces wiping the database file on every launch, so watched state,
+// scores and prepared-HLS pointers survive a restart.
+func Migrate(dbConn *gorm.DB) error {
+	if err := dbConn.AutoMigrate(&schemaMigration{}).Error; err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		var existing schemaMigration
+		if !dbConn.Where("version = ?", m.Version).First(&existing).RecordNotFound() {
+			continue
+		}
+
+		log.Infoln("applying migration", m.Version, m.Name)
+		if err := m.Up(dbConn); err != nil {
+			return err
+		}
+
+		if err := dbConn.Create(&schemaMigration{Version: m.Version}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "voodio.db")
+
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer db.Close()
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("first Migrate: %v", err)
+	}
+
+	var countAfterFirst int
+	db.Table("schema_migrations").Count(&countAfterFirst)
+	if countAfterFirst != len(migrations) {
+		t.Fatalf("got %d recorded migrations, want %d", countAfterFirst, len(migrations))
+	}
+
+	// Re-running Migrate against the same DB must not re-apply anything or
+	// error out, so a restart never wipes or re-creates the schema.
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	var countAfterSecond int
+	db.Table("schema_migrations").Count(&countAfterSecond)
+	if countAfterSecond != countAfterFirst {
+		t.Fatalf("got %d recorded migrations after re-run, want %d", countAfterSecond, countAfterFirst)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// SourceType distinguishes the kind of file an AlternateSource points at.
+type SourceType string
+
+const (
+	SourceTypeVideo    SourceType = "video"
+	SourceTypeAudio    SourceType = "audio"
+	SourceTypeSubtitle SourceType = "subtitle"
+)
+
+// AlternateSource is an extra file sitting next to a Movie's primary
+// BaseName: a second cut, a separate audio track, or a subtitle in another
+// language.
+type AlternateSource struct {
+	Name     string     `json:"name"`
+	Type     SourceType `json:"type"`
+	URL      string     `json:"url"`
+	Language string     `json:"language,omitempty"`
+}
+
+// AlternateSources is stored as a JSON-encoded TEXT column so a movie with
+// multi-audio tracks or several subtitle languages can be queried as a
+// single row.
+type AlternateSources []AlternateSource
+
+// Value implements driver.Valuer so gorm can write this as a TEXT column.
+func (s AlternateSources) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner so gorm can read this back from a TEXT column.
+func (s *AlternateSources) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("models: unsupported type for AlternateSources: %T", value)
+	}
+
+	if len(data) == 0 {
+		*s = nil
+		return nil
+	}
+
+	return json.Unmarshal(data, s)
+}
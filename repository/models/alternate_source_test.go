@@ -0,0 +1,49 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlternateSourcesValueScanRoundTrip(t *testing.T) {
+	sources := AlternateSources{
+		{Name: "movie.fr.srt", Type: SourceTypeSubtitle, URL: "/movies/movie.fr.srt", Language: "fr"},
+		{Name: "movie-alt.mkv", Type: SourceTypeVideo, URL: "/movies/movie-alt.mkv"},
+	}
+
+	value, err := sources.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	var roundTripped AlternateSources
+	if err := roundTripped.Scan(value); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(sources, roundTripped) {
+		t.Fatalf("got %+v, want %+v", roundTripped, sources)
+	}
+}
+
+func TestAlternateSourcesValueEmpty(t *testing.T) {
+	var sources AlternateSources
+
+	value, err := sources.Value()
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+	if value != "[]" {
+		t.Fatalf("got %v, want \"[]\"", value)
+	}
+}
+
+func TestAlternateSourcesScanNil(t *testing.T) {
+	sources := AlternateSources{{Name: "x"}}
+	if err := sources.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) error: %v", err)
+	}
+	if sources != nil {
+		t.Fatalf("got %+v, want nil", sources)
+	}
+}
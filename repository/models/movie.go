@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Movie represents a single video file discovered under the configured
+// movie path, along with the metadata voodio has derived or fetched for it.
+type Movie struct {
+	gorm.Model
+
+	DirPath       string `gorm:"type:varchar(1000)"`
+	DirName       string `gorm:"type:varchar(500)"`
+	CleanDirName  string `gorm:"type:varchar(500)"`
+	FileSize      float64
+	BaseName      string `gorm:"type:varchar(500)"`
+	CleanBaseName string `gorm:"type:varchar(500)"`
+	MimeType      string `gorm:"type:varchar(100)"`
+	IsGroupDir    bool
+	IsPrepared    bool
+
+	// Overview, Year and PosterPath are filled in by scrapeMovie once a
+	// metadata.Chain lookup succeeds. PosterPath points at the poster image
+	// cached on disk under the app dir, not the provider's remote URL.
+	Overview   string `gorm:"type:text"`
+	Year       int
+	PosterPath string `gorm:"type:varchar(500)"`
+
+	// IsInPrepare is set while a transcode job for this movie is running,
+	// so the web layer can show progress instead of allowing a duplicate
+	// "prepare" request to be queued.
+	IsInPrepare bool
+
+	// IsGone is set once the watcher detects the backing file/directory
+	// has been removed from disk, without deleting the row outright.
+	IsGone bool
+
+	// MoreSources holds sibling files discovered next to BaseName: extra
+	// video/audio tracks or subtitle languages, surfaced as selectable
+	// tracks instead of disconnected Movie/Subtitle rows.
+	MoreSources AlternateSources `gorm:"type:text"`
+
+	// Watched, Score, LastPlayedAt and PlaybackPositionSec let the web
+	// player resume playback and users rate what they've watched.
+	Watched             bool
+	Score               int
+	LastPlayedAt        time.Time
+	PlaybackPositionSec int
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Subtitle represents a subtitle file discovered next to a movie.
+type Subtitle struct {
+	gorm.Model
+
+	DirPath       string `gorm:"type:varchar(1000)"`
+	DirName       string `gorm:"type:varchar(500)"`
+	CleanDirName  string `gorm:"type:varchar(500)"`
+	BaseName      string `gorm:"type:varchar(500)"`
+	CleanBaseName string `gorm:"type:varchar(500)"`
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/slaveofcode/voodio/metadata"
+	"github.com/slaveofcode/voodio/pubsub"
+	"github.com/slaveofcode/voodio/workers"
+)
+
+// ServerConfig carries everything the web server needs to serve the API and
+// run transcode jobs, assembled once in main and passed to web.NewServer.
+type ServerConfig struct {
+	DB                *gorm.DB
+	Port              int
+	AppDir            string
+	ScreenResolutions []string
+
+	// FFmpegBin is the resolved path to the ffmpeg binary, used by the
+	// "prepare movie" flow to launch transcode jobs.
+	FFmpegBin string
+
+	// MetadataProviders is an ordered fallback chain tried for every movie
+	// scrape, e.g. tmdb,omdb,nfo.
+	MetadataProviders metadata.Chain
+
+	// WorkerPool runs the FFmpeg transcode jobs triggered by the
+	// "prepare movie" flow.
+	WorkerPool *workers.Pool
+
+	// EventBus carries import/transcode/library progress events out to any
+	// client connected to /ws/events.
+	EventBus *pubsub.Bus
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slaveofcode/voodio/collections"
+	"github.com/slaveofcode/voodio/repository/models"
+	"github.com/slaveofcode/voodio/web/config"
+	"github.com/slaveofcode/voodio/workers"
+)
+
+// HandlePrepareMovie queues a transcode job for the movie identified by the
+// movieId query param, so it can be played back as HLS. It responds
+// immediately; progress is reported separately over the event bus.
+func HandlePrepareMovie(cfg *config.ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("movieId"))
+		if err != nil {
+			http.Error(w, "invalid movieId", http.StatusBadRequest)
+			return
+		}
+
+		resolution := r.URL.Query().Get("resolution")
+		if resolution == "" {
+			resolution = "720p"
+		}
+
+		var movie models.Movie
+		if err := cfg.DB.First(&movie, id).Error; err != nil {
+			http.Error(w, "movie not found", http.StatusNotFound)
+			return
+		}
+
+		if movie.IsInPrepare {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		cfg.DB.Model(&movie).Update("is_in_prepare", true)
+
+		outputDir := filepath.Join(cfg.AppDir, "hls", strconv.Itoa(int(movie.ID)))
+		sourcePath := filepath.Join(movie.DirPath, movie.BaseName)
+
+		cfg.WorkerPool.Submit(workers.Job{
+			MovieID:    movie.ID,
+			Resolution: resolution,
+			Run: func(ctx context.Context, report func(percent float64)) error {
+				duration, durationErr := collections.ProbeDuration(cfg.FFmpegBin, sourcePath)
+				if durationErr != nil {
+					log.Errorln("unable to probe duration for", sourcePath, durationErr)
+				}
+
+				err := collections.DoExtraction(ctx, cfg.FFmpegBin, sourcePath, outputDir, resolution, duration, report)
+
+				update := map[string]interface{}{"is_in_prepare": false}
+				if err == nil {
+					update["is_prepared"] = true
+				}
+				cfg.DB.Model(&models.Movie{}).Where("id = ?", movie.ID).Updates(update)
+
+				return err
+			},
+		})
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
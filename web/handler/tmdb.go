@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/slaveofcode/voodio/repository/models"
+	"github.com/slaveofcode/voodio/web/config"
+)
+
+// HandleMoviePoster serves a movie's poster art. A movie that's already been
+// scraped is served straight from its cached PosterPath; otherwise this falls
+// back to an on-demand lookup against cfg.MetadataProviders - the same
+// fallback chain scrapeMovie uses - instead of making the client wait for the
+// background scrape pass to finish.
+func HandleMoviePoster(cfg *config.ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("movieId"))
+		if err != nil {
+			http.Error(w, "invalid movieId", http.StatusBadRequest)
+			return
+		}
+
+		var movie models.Movie
+		if err := cfg.DB.First(&movie, id).Error; err != nil {
+			http.Error(w, "movie not found", http.StatusNotFound)
+			return
+		}
+
+		if movie.PosterPath != "" {
+			http.ServeFile(w, r, movie.PosterPath)
+			return
+		}
+
+		meta, provider, err := cfg.MetadataProviders.Lookup(movie.CleanBaseName, movie.Year)
+		if err != nil || meta.PosterPath == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := provider.FetchPoster(meta.PosterPath)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		io.Copy(w, bytes.NewReader(data))
+	}
+}
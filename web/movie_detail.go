@@ -0,0 +1,52 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/slaveofcode/voodio/repository/models"
+)
+
+type movieDetail struct {
+	ID            uint          `json:"id"`
+	CleanBaseName string        `json:"clean_base_name"`
+	Overview      string        `json:"overview"`
+	Year          int           `json:"year"`
+	PosterPath    string        `json:"poster_path"`
+	Watched       bool          `json:"watched"`
+	Score         int           `json:"score"`
+	Tracks        []TrackOption `json:"tracks"`
+}
+
+// HandleMovieDetail serves a single movie's metadata along with its
+// selectable audio/subtitle tracks, built from MoreSources.
+func HandleMovieDetail(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("movieId"))
+		if err != nil {
+			http.Error(w, "invalid movieId", http.StatusBadRequest)
+			return
+		}
+
+		var movie models.Movie
+		if err := db.First(&movie, id).Error; err != nil {
+			http.Error(w, "movie not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(movieDetail{
+			ID:            movie.ID,
+			CleanBaseName: movie.CleanBaseName,
+			Overview:      movie.Overview,
+			Year:          movie.Year,
+			PosterPath:    movie.PosterPath,
+			Watched:       movie.Watched,
+			Score:         movie.Score,
+			Tracks:        BuildTrackOptions(&movie),
+		})
+	}
+}
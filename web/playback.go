@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/slaveofcode/voodio/repository/models"
+)
+
+// HandleUpdateScore handles PUT /movies/score?movieId=
+func HandleUpdateScore(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Score int `json:"score"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+
+		updateMovieByID(w, r, db, map[string]interface{}{"score": body.Score})
+	}
+}
+
+// HandleUpdateWatched handles PUT /movies/watched?movieId=
+func HandleUpdateWatched(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Watched bool `json:"watched"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+
+		updateMovieByID(w, r, db, map[string]interface{}{
+			"watched":        body.Watched,
+			"last_played_at": time.Now(),
+		})
+	}
+}
+
+// HandleUpdatePosition handles PUT /movies/position?movieId=
+func HandleUpdatePosition(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			PositionSec int `json:"position_sec"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+
+		updateMovieByID(w, r, db, map[string]interface{}{
+			"playback_position_sec": body.PositionSec,
+			"last_played_at":        time.Now(),
+		})
+	}
+}
+
+// updateMovieByID applies changes with a map instead of a struct, since
+// GORM's struct-based Updates silently skips zero-valued fields - which
+// would make HandleUpdateWatched unable to un-watch a movie, or
+// HandleUpdateScore unable to clear a score back to 0.
+func updateMovieByID(w http.ResponseWriter, r *http.Request, db *gorm.DB, changes map[string]interface{}) {
+	id, err := strconv.Atoi(r.URL.Query().Get("movieId"))
+	if err != nil {
+		http.Error(w, "invalid movieId", http.StatusBadRequest)
+		return
+	}
+
+	if err := db.Model(&models.Movie{}).Where("id = ?", id).Updates(changes).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// duplicateMovie is a single row returned by HandleListDuplicates.
+type duplicateMovie struct {
+	CleanBaseName string  `json:"clean_base_name"`
+	ID            uint    `json:"id"`
+	FileSize      float64 `json:"file_size"`
+}
+
+// HandleListDuplicates handles GET /movies/duplicates, returning movies
+// grouped by CleanBaseName with differing FileSize - useful for spotting the
+// same title downloaded more than once at different quality/size.
+func HandleListDuplicates(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var cleanBaseNames []string
+		db.Model(&models.Movie{}).
+			Where("clean_base_name != ?", "").
+			Group("clean_base_name").
+			Having("COUNT(DISTINCT file_size) > 1").
+			Pluck("clean_base_name", &cleanBaseNames)
+
+		var duplicates []duplicateMovie
+		if len(cleanBaseNames) > 0 {
+			db.Model(&models.Movie{}).
+				Where("clean_base_name in (?)", cleanBaseNames).
+				Order("clean_base_name").
+				Scan(&duplicates)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(duplicates)
+	}
+}
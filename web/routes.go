@@ -0,0 +1,23 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/slaveofcode/voodio/web/config"
+	"github.com/slaveofcode/voodio/web/handler"
+	"github.com/slaveofcode/voodio/web/ws"
+)
+
+// RegisterRoutes mounts every HTTP/WebSocket handler on mux, using plain
+// ?movieId= query params instead of a path-param router.
+func RegisterRoutes(mux *http.ServeMux, cfg *config.ServerConfig) {
+	mux.HandleFunc("/movies/detail", HandleMovieDetail(cfg.DB))
+	mux.HandleFunc("/movies/duplicates", HandleListDuplicates(cfg.DB))
+	mux.HandleFunc("/movies/score", HandleUpdateScore(cfg.DB))
+	mux.HandleFunc("/movies/watched", HandleUpdateWatched(cfg.DB))
+	mux.HandleFunc("/movies/position", HandleUpdatePosition(cfg.DB))
+	mux.HandleFunc("/movies/poster", handler.HandleMoviePoster(cfg))
+	mux.HandleFunc("/movies/prepare", handler.HandlePrepareMovie(cfg))
+
+	mux.HandleFunc("/ws/events", ws.HandleEvents(cfg.EventBus))
+}
@@ -0,0 +1,20 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/slaveofcode/voodio/web/config"
+)
+
+// NewServer builds the *http.Server main wires into its shutdown sequence,
+// with every route from RegisterRoutes mounted on a fresh mux.
+func NewServer(cfg *config.ServerConfig) *http.Server {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, cfg)
+
+	return &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.Port),
+		Handler: mux,
+	}
+}
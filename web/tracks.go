@@ -0,0 +1,35 @@
+package web
+
+import "github.com/slaveofcode/voodio/repository/models"
+
+// TrackOption is a selectable audio/subtitle track the player can attach
+// alongside a movie's primary HLS stream.
+type TrackOption struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	Language string `json:"language,omitempty"`
+}
+
+// BuildTrackOptions turns a movie's MoreSources into selectable audio and
+// subtitle tracks for the movie detail response. Alternate video sources are
+// skipped here since those are separate playable titles, not tracks of the
+// primary stream.
+func BuildTrackOptions(movie *models.Movie) []TrackOption {
+	var tracks []TrackOption
+
+	for _, src := range movie.MoreSources {
+		if src.Type == models.SourceTypeVideo {
+			continue
+		}
+
+		tracks = append(tracks, TrackOption{
+			Name:     src.Name,
+			Type:     string(src.Type),
+			URL:      src.URL,
+			Language: src.Language,
+		})
+	}
+
+	return tracks
+}
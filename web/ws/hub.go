@@ -0,0 +1,84 @@
+package ws
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slaveofcode/voodio/pubsub"
+)
+
+const (
+	pingInterval = 30 * time.Second
+	writeWait    = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// HandleEvents upgrades the request to a WebSocket and streams every
+// Message published on bus as JSON, until the client disconnects or stops
+// responding to pings.
+func HandleEvents(bus *pubsub.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Errorln("ws: upgrade failed:", err)
+			return
+		}
+		defer conn.Close()
+
+		messages, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+
+		conn.SetReadDeadline(time.Now().Add(pingInterval * 2))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(pingInterval * 2))
+			return nil
+		})
+
+		clientGone := make(chan struct{})
+		go func() {
+			drainClient(conn)
+			close(clientGone)
+		}()
+
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-clientGone:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := conn.WriteJSON(msg); err != nil {
+					return
+				}
+			case <-ticker.C:
+				conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// drainClient discards anything the client sends so pong frames - and the
+// read deadline they reset - are actually processed.
+func drainClient(conn *websocket.Conn) {
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
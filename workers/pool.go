@@ -0,0 +1,162 @@
+package workers
+
+import (
+	"context"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/slaveofcode/voodio/pubsub"
+)
+
+// Job is a single FFmpeg transcode unit submitted to the Pool. Run is handed
+// a report func so it can surface percentage progress as it parses the
+// encoder's `-progress pipe:1` output.
+type Job struct {
+	MovieID    uint
+	Resolution string
+	Run        func(ctx context.Context, report func(percent float64)) error
+}
+
+// Pool is a bounded pool of workers that run FFmpeg transcode Jobs, wired
+// into a shutdown context and guarded against repeated panics by a Safeguard.
+type Pool struct {
+	size      int
+	jobs      chan Job
+	reload    chan int
+	safeguard *Safeguard
+	bus       *pubsub.Bus
+}
+
+// NewPool creates a Pool with the given number of workers and event bus. A
+// size <= 0 falls back to runtime.NumCPU(). bus may be nil, in which case
+// transcode events are simply not published.
+func NewPool(size int, bus *pubsub.Bus) *Pool {
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+
+	return &Pool{
+		size:      size,
+		jobs:      make(chan Job),
+		reload:    make(chan int, size),
+		safeguard: NewSafeguard(MaxEventCount, MaxEventDelay),
+		bus:       bus,
+	}
+}
+
+// Submit enqueues a job for the next free worker without blocking the
+// caller, so an HTTP handler queuing a transcode can respond immediately.
+// If every worker is currently busy, the job is handed off to a goroutine
+// that waits for a free slot instead of holding up the caller.
+func (p *Pool) Submit(job Job) {
+	select {
+	case p.jobs <- job:
+	default:
+		go func() { p.jobs <- job }()
+	}
+}
+
+// Start launches the worker goroutines, the reload supervisor and the
+// safeguard, until ctx is cancelled (voodio's own shutdown signal).
+func (p *Pool) Start(ctx context.Context) {
+	go p.safeguard.Watch(ctx)
+
+	for id := 0; id < p.size; id++ {
+		go p.runWorker(ctx, id)
+	}
+
+	go p.superviseReloads(ctx)
+}
+
+// superviseReloads restarts a worker whenever its goroutine reports itself
+// dead after a panic, so the pool keeps its configured size instead of
+// slowly losing workers to bad input files.
+func (p *Pool) superviseReloads(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-p.reload:
+			go p.runWorker(ctx, id)
+		}
+	}
+}
+
+func (p *Pool) runWorker(ctx context.Context, id int) {
+	entry := log.WithField("worker_id", id)
+	entry.Infoln("worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			entry.Infoln("worker stopped")
+			return
+		case job := <-p.jobs:
+			if !p.runJob(ctx, id, job) {
+				// The job panicked - this worker goroutine is considered
+				// unhealthy and exits; the supervisor spins up its
+				// replacement instead of this loop continuing.
+				entry.Errorln("worker exiting after panic, requesting reload")
+				p.reload <- id
+				return
+			}
+		}
+	}
+}
+
+// runJob runs a single job and reports whether the worker is still healthy
+// afterwards - false means job.Run panicked and the caller should let this
+// worker goroutine die.
+func (p *Pool) runJob(ctx context.Context, workerID int, job Job) (ok bool) {
+	entry := log.WithFields(log.Fields{
+		"worker_id":  workerID,
+		"movie_id":   job.MovieID,
+		"resolution": job.Resolution,
+	})
+
+	ok = true
+	defer func() {
+		if r := recover(); r != nil {
+			entry.Errorln("transcode panicked:", r)
+			p.safeguard.ReportFailure()
+			p.publish(pubsub.EventTranscodeFailed, job, nil)
+			ok = false
+		}
+	}()
+
+	entry.Infoln("transcode started")
+	p.publish(pubsub.EventTranscodeStarted, job, nil)
+
+	report := func(percent float64) {
+		p.publish(pubsub.EventTranscodeProgress, job, percent)
+	}
+
+	if err := job.Run(ctx, report); err != nil {
+		// An ordinary transcode failure (bad source file, ffmpeg exit error)
+		// isn't a sign the worker itself is unhealthy, so it doesn't count
+		// against the Safeguard - only panics do.
+		entry.Errorln("transcode failed:", err)
+		p.publish(pubsub.EventTranscodeFailed, job, err.Error())
+		return
+	}
+
+	entry.Infoln("transcode finished")
+	p.publish(pubsub.EventTranscodeDone, job, nil)
+	return
+}
+
+func (p *Pool) publish(eventType string, job Job, extra interface{}) {
+	if p.bus == nil {
+		return
+	}
+
+	p.bus.Publish(pubsub.Message{
+		Type: eventType,
+		Payload: map[string]interface{}{
+			"movie_id":   job.MovieID,
+			"resolution": job.Resolution,
+			"progress":   extra,
+		},
+	})
+}
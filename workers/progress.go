@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseFFmpegProgress reads an ffmpeg `-progress pipe:1` stream and calls
+// report with the completion percentage (0-100) for every progress block,
+// using totalDurationSec (the source media's duration) to turn the
+// reported out_time_ms into a percentage.
+func ParseFFmpegProgress(r io.Reader, totalDurationSec float64, report func(percent float64)) error {
+	scanner := bufio.NewScanner(r)
+
+	var outTimeMs int64
+	for scanner.Scan() {
+		key, value, ok := splitProgressLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "out_time_ms":
+			outTimeMs, _ = strconv.ParseInt(value, 10, 64)
+		case "progress":
+			if totalDurationSec > 0 {
+				percent := (float64(outTimeMs) / 1000 / 1000) / totalDurationSec * 100
+				report(percent)
+			}
+			if value == "end" {
+				return scanner.Err()
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+func splitProgressLine(line string) (key, value string, ok bool) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+}
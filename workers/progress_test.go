@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFFmpegProgressReportsPercent(t *testing.T) {
+	input := strings.Join([]string{
+		"frame=1",
+		"out_time_ms=50000000",
+		"progress=continue",
+		"out_time_ms=100000000",
+		"progress=end",
+		"",
+	}, "\n")
+
+	var reported []float64
+	err := ParseFFmpegProgress(strings.NewReader(input), 100, func(percent float64) {
+		reported = append(reported, percent)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reported) != 2 {
+		t.Fatalf("got %d progress reports, want 2", len(reported))
+	}
+	if reported[0] != 50 {
+		t.Fatalf("first report = %v, want 50", reported[0])
+	}
+	if reported[1] != 100 {
+		t.Fatalf("second report = %v, want 100", reported[1])
+	}
+}
+
+func TestParseFFmpegProgressSkipsWhenDurationUnknown(t *testing.T) {
+	input := "out_time_ms=50000000\nprogress=end\n"
+
+	called := false
+	err := ParseFFmpegProgress(strings.NewReader(input), 0, func(percent float64) {
+		called = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Fatal("expected report not to be called when totalDurationSec is unknown")
+	}
+}
@@ -0,0 +1,84 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxEventCount is how many transcode panics are tolerated within
+	// MaxEventDelay before the whole process is considered unsafe to keep
+	// running.
+	MaxEventCount = 5
+	// MaxEventDelay is the sliding window panics are counted in.
+	MaxEventDelay = time.Minute
+)
+
+// Safeguard tracks transcode panics reported by Pool workers. If
+// more than MaxEventCount happen within MaxEventDelay, it fatally exits the
+// process rather than letting a bad file keep crashing workers forever.
+type Safeguard struct {
+	maxCount int
+	window   time.Duration
+
+	mu     sync.Mutex
+	events []time.Time
+
+	failures chan struct{}
+}
+
+// NewSafeguard creates a Safeguard with the given threshold and window.
+func NewSafeguard(maxCount int, window time.Duration) *Safeguard {
+	return &Safeguard{
+		maxCount: maxCount,
+		window:   window,
+		failures: make(chan struct{}, maxCount*2),
+	}
+}
+
+// ReportFailure records a transcode panic.
+func (s *Safeguard) ReportFailure() {
+	select {
+	case s.failures <- struct{}{}:
+	default:
+		// channel full: this report is dropped, while the maxCount*2
+		// reports already buffered are still counted by Watch
+	}
+}
+
+// Watch consumes reported failures and fatally exits the process once too
+// many happen within the configured window.
+func (s *Safeguard) Watch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.failures:
+			if s.recordAndCheck() {
+				log.Fatalln("too many transcode failures, exiting")
+			}
+		}
+	}
+}
+
+func (s *Safeguard) recordAndCheck() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.events = append(s.events, now)
+
+	cutoff := now.Add(-s.window)
+	kept := s.events[:0]
+	for _, t := range s.events {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	s.events = kept
+
+	return len(s.events) > s.maxCount
+}
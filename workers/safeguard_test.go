@@ -0,0 +1,31 @@
+package workers
+
+import "testing"
+
+func TestSafeguardRecordAndCheckTripsAfterMaxCount(t *testing.T) {
+	s := NewSafeguard(3, MaxEventDelay)
+
+	for i := 0; i < 3; i++ {
+		if s.recordAndCheck() {
+			t.Fatalf("tripped too early on event %d", i+1)
+		}
+	}
+
+	if !s.recordAndCheck() {
+		t.Fatal("expected safeguard to trip once count exceeds maxCount")
+	}
+}
+
+func TestSafeguardRecordAndCheckForgetsOldEvents(t *testing.T) {
+	s := NewSafeguard(1, 0)
+
+	if s.recordAndCheck() {
+		t.Fatal("did not expect a trip on the first event")
+	}
+
+	// window is 0, so the first event is immediately outside it and should
+	// be forgotten before the second is recorded.
+	if s.recordAndCheck() {
+		t.Fatal("expected stale events to be pruned instead of accumulating")
+	}
+}